@@ -2,15 +2,31 @@ package aws
 
 import (
 	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/require"
 )
 
+// maxConcurrentSsmRequests bounds the number of goroutines used when fanning
+// out batch parameter operations so that we don't trip the SSM API's
+// throttling limits.
+const maxConcurrentSsmRequests = 10
+
+// maxParametersPerDeleteRequest is the maximum number of parameter names the
+// DeleteParameters API accepts in a single call.
+const maxParametersPerDeleteRequest = 10
+
 // GetParameter retrieves the latest version of SSM Parameter at keyName with decryption.
 func GetParameter(t *testing.T, awsRegion string, keyName string) string {
 	keyValue, err := GetParameterE(t, awsRegion, keyName)
@@ -56,6 +72,244 @@ func PutParameterE(t *testing.T, awsRegion string, keyName string, keyDescriptio
 	return *resp.Version, nil
 }
 
+// PutParameterInput represents the full set of options accepted when writing
+// an SSM Parameter via PutParameterWithOptionsE.
+type PutParameterInput struct {
+	// Name is the name of the parameter.
+	Name string
+	// Description is a human readable description of the parameter.
+	Description string
+	// Value is the value of the parameter.
+	Value string
+	// Type is one of String, StringList, or SecureString. Defaults to
+	// SecureString if left blank.
+	Type string
+	// KMSKeyId is the KMS key used to encrypt SecureString parameters. If
+	// left blank, the account's default SSM KMS key is used.
+	KMSKeyId string
+	// Overwrite allows an existing parameter of the same name to be
+	// replaced with a new value.
+	Overwrite bool
+	// Tier is one of Standard, Advanced, or Intelligent-Tiering. Defaults to
+	// Standard if left blank.
+	Tier string
+	// AllowedPattern is a regular expression used to validate the parameter
+	// value.
+	AllowedPattern string
+	// Tags are applied to the parameter on creation. They are ignored by
+	// the SSM API when Overwrite is true.
+	Tags map[string]string
+}
+
+// PutParameterWithOptionsE creates or updates an SSM Parameter using the
+// full set of options exposed by the underlying PutParameter API, such as
+// the parameter Type, KMSKeyId, Tier, AllowedPattern, and Tags.
+func PutParameterWithOptionsE(t *testing.T, awsRegion string, input PutParameterInput) (int64, error) {
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return 0, err
+	}
+
+	parameterType := input.Type
+	if parameterType == "" {
+		parameterType = ssm.ParameterTypeSecureString
+	}
+
+	putParameterInput := &ssm.PutParameterInput{
+		Name:        aws.String(input.Name),
+		Description: aws.String(input.Description),
+		Value:       aws.String(input.Value),
+		Type:        aws.String(parameterType),
+		Overwrite:   aws.Bool(input.Overwrite),
+	}
+
+	if input.AllowedPattern != "" {
+		putParameterInput.AllowedPattern = aws.String(input.AllowedPattern)
+	}
+
+	if input.KMSKeyId != "" {
+		putParameterInput.KeyId = aws.String(input.KMSKeyId)
+	}
+
+	if input.Tier != "" {
+		putParameterInput.Tier = aws.String(input.Tier)
+	}
+
+	if len(input.Tags) > 0 {
+		var tags []*ssm.Tag
+		for key, value := range input.Tags {
+			tags = append(tags, &ssm.Tag{Key: aws.String(key), Value: aws.String(value)})
+		}
+		putParameterInput.Tags = tags
+	}
+
+	resp, err := ssmClient.PutParameter(putParameterInput)
+	if err != nil {
+		return 0, err
+	}
+
+	return *resp.Version, nil
+}
+
+// GetParameterHistoryE returns every recorded version of the SSM Parameter
+// at keyName, oldest first, following NextToken pagination.
+func GetParameterHistoryE(t *testing.T, awsRegion string, keyName string) ([]*ssm.ParameterHistory, error) {
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []*ssm.ParameterHistory
+	var nextToken *string
+
+	for {
+		resp, err := ssmClient.GetParameterHistory(&ssm.GetParameterHistoryInput{
+			Name:           aws.String(keyName),
+			WithDecryption: aws.Bool(true),
+			NextToken:      nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		history = append(history, resp.Parameters...)
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return history, nil
+}
+
+// GetParameterVersionE retrieves a specific version of the SSM Parameter at
+// keyName with decryption, so that tests can assert against a historical
+// value rather than only the latest one.
+func GetParameterVersionE(t *testing.T, awsRegion string, keyName string, version int64) (string, error) {
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	qualifiedName := fmt.Sprintf("%s:%d", keyName, version)
+
+	resp, err := ssmClient.GetParameter(&ssm.GetParameterInput{Name: aws.String(qualifiedName), WithDecryption: aws.Bool(true)})
+	if err != nil {
+		return "", err
+	}
+
+	return *resp.Parameter.Value, nil
+}
+
+// PutParametersE creates or updates the given parameters, fanning the writes
+// out across a bounded number of goroutines. Each parameter supports the
+// full set of options exposed by PutParameterWithOptionsE (Type, KMSKeyId,
+// Tier, AllowedPattern, Tags), so bulk writers aren't stuck with
+// SecureString-only parameters. It returns an error if any of the
+// underlying PutParameter calls fail.
+func PutParametersE(t *testing.T, awsRegion string, parameters []PutParameterInput) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var allErrs *multierror.Error
+
+	sem := make(chan struct{}, maxConcurrentSsmRequests)
+
+	for _, parameter := range parameters {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(parameter PutParameterInput) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if _, err := PutParameterWithOptionsE(t, awsRegion, parameter); err != nil {
+				mu.Lock()
+				allErrs = multierror.Append(allErrs, fmt.Errorf("failed to put parameter %s: %v", parameter.Name, err))
+				mu.Unlock()
+			}
+		}(parameter)
+	}
+
+	wg.Wait()
+
+	return allErrs.ErrorOrNil()
+}
+
+// GetParametersByPathE returns every parameter under path, with decryption,
+// automatically following NextToken pagination. If recursive is true,
+// parameters in child paths are included as well.
+func GetParametersByPathE(t *testing.T, awsRegion string, path string, recursive bool) (map[string]string, error) {
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := map[string]string{}
+	var nextToken *string
+
+	for {
+		resp, err := ssmClient.GetParametersByPath(&ssm.GetParametersByPathInput{
+			Path:           aws.String(path),
+			Recursive:      aws.Bool(recursive),
+			WithDecryption: aws.Bool(true),
+			NextToken:      nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, parameter := range resp.Parameters {
+			parameters[*parameter.Name] = *parameter.Value
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return parameters, nil
+}
+
+// DeleteParametersE deletes the given parameter names, chunking them into
+// groups of maxParametersPerDeleteRequest to respect the DeleteParameters API
+// limit.
+func DeleteParametersE(t *testing.T, awsRegion string, names []string) error {
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	var allErrs *multierror.Error
+
+	for _, chunk := range chunkStrings(names, maxParametersPerDeleteRequest) {
+		_, err := ssmClient.DeleteParameters(&ssm.DeleteParametersInput{
+			Names: aws.StringSlice(chunk),
+		})
+		if err != nil {
+			allErrs = multierror.Append(allErrs, err)
+		}
+	}
+
+	return allErrs.ErrorOrNil()
+}
+
+// chunkStrings splits names into slices of at most size elements. It returns
+// no chunks for an empty input, rather than a single empty chunk.
+func chunkStrings(names []string, size int) [][]string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	var chunks [][]string
+	for size < len(names) {
+		names, chunks = names[size:], append(chunks, names[0:size:size])
+	}
+	chunks = append(chunks, names)
+	return chunks
+}
+
 // NewSsmClient creates a SSM client.
 func NewSsmClient(t *testing.T, region string) *ssm.SSM {
 	client, err := NewSsmClientE(t, region)
@@ -65,12 +319,111 @@ func NewSsmClient(t *testing.T, region string) *ssm.SSM {
 
 // NewSsmClientE creates an SSM client.
 func NewSsmClientE(t *testing.T, region string) (*ssm.SSM, error) {
+	return NewSsmClientWithOptionsE(t, region, SsmClientOptions{})
+}
+
+// SsmClientOptions configures the retry behavior of a client created by
+// NewSsmClientWithOptionsE.
+type SsmClientOptions struct {
+	// RetryMode is either "standard" or "adaptive". In "adaptive" mode,
+	// every throttling retry delay is twice what "standard" mode uses. No
+	// client-side token bucket or send-rate limiting is applied in either
+	// mode. Defaults to "standard".
+	RetryMode string
+	// MaxRetries is the maximum number of times a request will be retried.
+	// Defaults to the SDK's default client retry count if zero.
+	MaxRetries int
+	// MinThrottleDelay is the minimum delay applied before retrying a
+	// throttled request. Defaults to 500ms if zero.
+	MinThrottleDelay time.Duration
+}
+
+const defaultMinThrottleDelay = 500 * time.Millisecond
+
+// defaultMaxRetries mirrors the SDK's own default client retry count, used
+// when SsmClientOptions.MaxRetries is left at its zero value.
+const defaultMaxRetries = 3
+
+// maxTransient403Retries bounds how many times a 403 is treated as
+// transient, to tolerate eventually-consistent IAM credentials just after a
+// role assumption without masking a genuine permissions error forever.
+const maxTransient403Retries = 3
+
+// NewSsmClientWithOptionsE creates an SSM client whose retry behavior is
+// tuned for CI environments where SSM is called in parallel and regularly
+// throttles. It always retries 5xx and throttling errors with exponential
+// backoff and jitter, and treats HTTP 403 as transient for a bounded number
+// of attempts.
+func NewSsmClientWithOptionsE(t *testing.T, region string, options SsmClientOptions) (*ssm.SSM, error) {
 	sess, err := NewAuthenticatedSession(region)
 	if err != nil {
 		return nil, err
 	}
 
-	return ssm.New(sess), nil
+	minThrottleDelay := options.MinThrottleDelay
+	if minThrottleDelay == 0 {
+		minThrottleDelay = defaultMinThrottleDelay
+	}
+
+	maxRetries := options.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	retryer := &ssmRetryer{
+		DefaultRetryer: client.DefaultRetryer{
+			NumMaxRetries: maxRetries,
+		},
+		adaptive:         options.RetryMode == "adaptive",
+		minThrottleDelay: minThrottleDelay,
+	}
+
+	return ssm.New(sess, aws.NewConfig().WithMaxRetries(retryer.NumMaxRetries).WithRetryer(retryer)), nil
+}
+
+// ssmRetryer extends the SDK's default retryer so that throttling errors
+// back off more aggressively and HTTP 403s are tolerated for a bounded
+// number of attempts, to smooth over eventually-consistent IAM credentials
+// right after a role assumption.
+type ssmRetryer struct {
+	client.DefaultRetryer
+	adaptive         bool
+	minThrottleDelay time.Duration
+}
+
+// ShouldRetry retries 5xx errors and throttling errors as the default
+// retryer does, and additionally retries HTTP 403 responses for up to
+// maxTransient403Retries attempts.
+func (r *ssmRetryer) ShouldRetry(req *request.Request) bool {
+	if req.Error != nil {
+		if aerr, ok := req.Error.(awserr.Error); ok {
+			if aerr.Code() == "AccessDenied" || (req.HTTPResponse != nil && req.HTTPResponse.StatusCode == 403) {
+				return req.RetryCount < maxTransient403Retries
+			}
+		}
+	}
+
+	return r.DefaultRetryer.ShouldRetry(req)
+}
+
+// RetryRules returns the delay to wait before the next retry attempt. For
+// throttling errors it applies exponential backoff, jitter, and enforces
+// minThrottleDelay as a floor; in adaptive mode the backoff grows faster to
+// shed load sooner under sustained throttling.
+func (r *ssmRetryer) RetryRules(req *request.Request) time.Duration {
+	if aerr, ok := req.Error.(awserr.Error); ok && request.IsErrorThrottle(aerr) {
+		exponent := req.RetryCount
+		if r.adaptive {
+			exponent++
+		}
+
+		delay := r.minThrottleDelay * time.Duration(1<<uint(exponent))
+		jitter := time.Duration(rand.Int63n(int64(delay) / 2))
+
+		return delay + jitter
+	}
+
+	return r.DefaultRetryer.RetryRules(req)
 }
 
 // WaitForInstance waits until an instance get registered in the SSM inventory
@@ -87,15 +440,18 @@ func WaitForInstanceE(t *testing.T, region string, instanceID string, timeout ti
 	maxRetries := int(timeout.Seconds() / timeBetweenRetries.Seconds())
 	description := fmt.Sprintf("Waiting for %s to appear in the SSM inventory", instanceID)
 
-	_, err := retry.DoWithRetryE(t, description, maxRetries, timeBetweenRetries, func() (string, error) {
-		client, err := NewSsmClientE(t, region)
-		if err != nil {
-			return "", fmt.Errorf("failed to get SSM client: %v", err)
-		}
+	// Use the adaptive retryer so this polling loop doesn't spuriously fail
+	// when SSM throttles under CI parallelism.
+	ssmClient, err := NewSsmClientWithOptionsE(t, region, SsmClientOptions{RetryMode: "adaptive"})
+	if err != nil {
+		return fmt.Errorf("failed to get SSM client: %v", err)
+	}
+
+	_, err = retry.DoWithRetryE(t, description, maxRetries, timeBetweenRetries, func() (string, error) {
 		key := "AWS:InstanceInformation.InstanceId"
 		t := "Equal"
 		values := []*string{&instanceID}
-		req, resp := client.GetInventoryRequest(&ssm.GetInventoryInput{
+		req, resp := ssmClient.GetInventoryRequest(&ssm.GetInventoryInput{
 			Filters: []*ssm.InventoryFilter{
 				{
 					Key:    &key,
@@ -117,3 +473,336 @@ func WaitForInstanceE(t *testing.T, region string, instanceID string, timeout ti
 
 	return err
 }
+
+// defaultRunCommandDocument is the SSM document used by RunCommandOnInstanceE
+// and RunCommandOnTargetsE when no document name is given.
+const defaultRunCommandDocument = "AWS-RunShellScript"
+
+// CommandResult holds the outcome of an SSM Run Command invocation on a
+// single instance.
+type CommandResult struct {
+	Status                string
+	StandardOutputContent string
+	StandardErrorContent  string
+	ResponseCode          int64
+}
+
+// isTerminalCommandStatus returns true once an invocation has finished
+// running, successfully or not.
+func isTerminalCommandStatus(status string) bool {
+	switch status {
+	case ssm.CommandInvocationStatusSuccess,
+		ssm.CommandInvocationStatusCancelled,
+		ssm.CommandInvocationStatusTimedOut,
+		ssm.CommandInvocationStatusFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// RunCommandOnInstanceE runs documentName (AWS-RunShellScript by default) on
+// instanceID with the given parameters, waits up to timeout for it to reach
+// a terminal state, and returns its stdout, stderr, and exit code. This lets
+// a terratest assert on instance bootstrapping (user-data, cloud-init,
+// Ansible) rather than only on presence in the SSM inventory.
+func RunCommandOnInstanceE(t *testing.T, awsRegion string, instanceID string, documentName string, parameters map[string][]string, timeout time.Duration) (*CommandResult, error) {
+	if documentName == "" {
+		documentName = defaultRunCommandDocument
+	}
+
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ssmClient.SendCommand(&ssm.SendCommandInput{
+		InstanceIds:  aws.StringSlice([]string{instanceID}),
+		DocumentName: aws.String(documentName),
+		Parameters:   toSsmParameters(parameters),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return WaitForCommandE(t, awsRegion, *resp.Command.CommandId, instanceID, timeout)
+}
+
+// RunCommandOnTargetsE runs documentName (AWS-RunShellScript by default)
+// against every instance matched by targets (e.g. a tag-based filter) and
+// returns a CommandResult per matched instance ID once every invocation has
+// reached a terminal state or timeout elapses.
+func RunCommandOnTargetsE(t *testing.T, awsRegion string, targets []*ssm.Target, documentName string, parameters map[string][]string, timeout time.Duration) (map[string]*CommandResult, error) {
+	if documentName == "" {
+		documentName = defaultRunCommandDocument
+	}
+
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ssmClient.SendCommand(&ssm.SendCommandInput{
+		Targets:      targets,
+		DocumentName: aws.String(documentName),
+		Parameters:   toSsmParameters(parameters),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	commandID := *resp.Command.CommandId
+	expectedTargetCount := aws.Int64Value(resp.Command.TargetCount)
+
+	timeBetweenRetries := 5 * time.Second
+	maxRetries := int(timeout.Seconds()/timeBetweenRetries.Seconds()) + 1
+	description := fmt.Sprintf("Waiting for command %s to reach all %d targets", commandID, expectedTargetCount)
+
+	var invocations []*ssm.CommandInvocation
+	_, err = retry.DoWithRetryE(t, description, maxRetries, timeBetweenRetries, func() (string, error) {
+		var listed []*ssm.CommandInvocation
+		var nextToken *string
+		for {
+			listResp, err := ssmClient.ListCommandInvocations(&ssm.ListCommandInvocationsInput{
+				CommandId: aws.String(commandID),
+				NextToken: nextToken,
+			})
+			if err != nil {
+				return "", err
+			}
+
+			listed = append(listed, listResp.CommandInvocations...)
+
+			if listResp.NextToken == nil {
+				break
+			}
+			nextToken = listResp.NextToken
+		}
+
+		// SSM registers invocations against a fleet target asynchronously, so
+		// a single ListCommandInvocations call can observe only a subset of
+		// the fleet. Keep polling until every targeted instance has one.
+		if int64(len(listed)) < expectedTargetCount {
+			return "", fmt.Errorf("command %s has only reached %d of %d targets", commandID, len(listed), expectedTargetCount)
+		}
+
+		invocations = listed
+		return "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Wait on every instance's invocation concurrently, bounded by the same
+	// deadline, so a slow or stuck instance doesn't push the total runtime
+	// of this fan-out toward N x timeout.
+	deadline := time.Now().Add(timeout)
+
+	results := map[string]*CommandResult{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var allErrs *multierror.Error
+
+	sem := make(chan struct{}, maxConcurrentSsmRequests)
+
+	for _, invocation := range invocations {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(instanceID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			remaining := time.Until(deadline)
+			if remaining < 0 {
+				remaining = 0
+			}
+
+			result, err := WaitForCommandE(t, awsRegion, commandID, instanceID, remaining)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				allErrs = multierror.Append(allErrs, fmt.Errorf("instance %s: %v", instanceID, err))
+				return
+			}
+			results[instanceID] = result
+		}(*invocation.InstanceId)
+	}
+
+	wg.Wait()
+
+	return results, allErrs.ErrorOrNil()
+}
+
+// WaitForCommandE polls GetCommandInvocation for the given commandID and
+// instanceID until the invocation reaches a terminal status or timeout
+// elapses, then returns its result.
+func WaitForCommandE(t *testing.T, awsRegion string, commandID string, instanceID string, timeout time.Duration) (*CommandResult, error) {
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	timeBetweenRetries := 5 * time.Second
+	maxRetries := int(timeout.Seconds()/timeBetweenRetries.Seconds()) + 1
+	description := fmt.Sprintf("Waiting for command %s on instance %s to complete", commandID, instanceID)
+
+	var invocation *ssm.GetCommandInvocationOutput
+	_, err = retry.DoWithRetryE(t, description, maxRetries, timeBetweenRetries, func() (string, error) {
+		resp, err := ssmClient.GetCommandInvocation(&ssm.GetCommandInvocationInput{
+			CommandId:  aws.String(commandID),
+			InstanceId: aws.String(instanceID),
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if !isTerminalCommandStatus(aws.StringValue(resp.Status)) {
+			return "", fmt.Errorf("command %s on instance %s is still %s", commandID, instanceID, aws.StringValue(resp.Status))
+		}
+
+		invocation = resp
+		return "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CommandResult{
+		Status:                aws.StringValue(invocation.Status),
+		StandardOutputContent: aws.StringValue(invocation.StandardOutputContent),
+		StandardErrorContent:  aws.StringValue(invocation.StandardErrorContent),
+		ResponseCode:          aws.Int64Value(invocation.ResponseCode),
+	}, nil
+}
+
+// toSsmParameters converts a map[string][]string into the map[string][]*string
+// shape expected by the SendCommand API.
+func toSsmParameters(parameters map[string][]string) map[string][]*string {
+	if len(parameters) == 0 {
+		return nil
+	}
+
+	out := make(map[string][]*string, len(parameters))
+	for key, values := range parameters {
+		out[key] = aws.StringSlice(values)
+	}
+	return out
+}
+
+// ListParametersUnderPathE returns the metadata (name, type, tier, KMS key
+// id) of every parameter under path, following NextToken pagination. If
+// recursive is true, parameters in child paths are included as well.
+func ListParametersUnderPathE(t *testing.T, awsRegion string, path string, recursive bool) ([]*ssm.ParameterMetadata, error) {
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	option := "OneLevel"
+	if recursive {
+		option = "Recursive"
+	}
+
+	var parameters []*ssm.ParameterMetadata
+	var nextToken *string
+
+	for {
+		resp, err := ssmClient.DescribeParameters(&ssm.DescribeParametersInput{
+			ParameterFilters: []*ssm.ParameterStringFilter{
+				{
+					Key:    aws.String("Path"),
+					Option: aws.String(option),
+					Values: aws.StringSlice([]string{path}),
+				},
+			},
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		parameters = append(parameters, resp.Parameters...)
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return parameters, nil
+}
+
+// DeleteParametersUnderPathE deletes every parameter under path, paginating
+// through the namespace and chunking the deletes to respect the
+// DeleteParameters API limit. This lets a terratest tear down an entire test
+// namespace in a defer without hand-written loops.
+func DeleteParametersUnderPathE(t *testing.T, awsRegion string, path string) error {
+	parameters, err := ListParametersUnderPathE(t, awsRegion, path, true)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(parameters))
+	for _, parameter := range parameters {
+		names = append(names, *parameter.Name)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	return DeleteParametersE(t, awsRegion, names)
+}
+
+// CopyParameterTreeE copies every parameter under srcPath in srcRegion to
+// the equivalent path under dstPath in dstRegion, preserving each
+// parameter's type and tier. A SecureString parameter's KMS key is only
+// carried over when it's an alias (e.g. "alias/my-key"); a source region's
+// concrete key id/ARN is region-scoped and won't exist in dstRegion, so it
+// is dropped in favor of dstRegion's default SSM key. This lets a terratest
+// seed a multi-region fixture from a single source namespace.
+func CopyParameterTreeE(t *testing.T, srcRegion string, srcPath string, dstRegion string, dstPath string) error {
+	parameters, err := ListParametersUnderPathE(t, srcRegion, srcPath, true)
+	if err != nil {
+		return err
+	}
+
+	var allErrs *multierror.Error
+
+	for _, parameter := range parameters {
+		srcName := *parameter.Name
+		value, err := GetParameterE(t, srcRegion, srcName)
+		if err != nil {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("failed to read %s: %v", srcName, err))
+			continue
+		}
+
+		dstName := dstPath + strings.TrimPrefix(srcName, srcPath)
+
+		// A source region's concrete KMS key id/ARN is region-scoped and
+		// almost never exists in dstRegion, so only carry it over when it's
+		// an alias, which callers are expected to have created in both
+		// regions ahead of time. A bare key id/ARN falls back to dstRegion's
+		// default SSM key.
+		keyID := aws.StringValue(parameter.KeyId)
+		if !strings.HasPrefix(keyID, "alias/") {
+			keyID = ""
+		}
+
+		_, err = PutParameterWithOptionsE(t, dstRegion, PutParameterInput{
+			Name:      dstName,
+			Value:     value,
+			Type:      aws.StringValue(parameter.Type),
+			KMSKeyId:  keyID,
+			Tier:      aws.StringValue(parameter.Tier),
+			Overwrite: true,
+		})
+		if err != nil {
+			allErrs = multierror.Append(allErrs, fmt.Errorf("failed to write %s: %v", dstName, err))
+		}
+	}
+
+	return allErrs.ErrorOrNil()
+}
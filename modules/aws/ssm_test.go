@@ -0,0 +1,183 @@
+package aws
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkStrings(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    []string
+		size     int
+		expected [][]string
+	}{
+		{
+			name:     "empty input produces no chunks",
+			input:    []string{},
+			size:     10,
+			expected: nil,
+		},
+		{
+			name:     "nil input produces no chunks",
+			input:    nil,
+			size:     10,
+			expected: nil,
+		},
+		{
+			name:     "input smaller than size produces one chunk",
+			input:    []string{"a", "b"},
+			size:     10,
+			expected: [][]string{{"a", "b"}},
+		},
+		{
+			name:     "input evenly divisible by size produces exact chunks",
+			input:    []string{"a", "b", "c", "d"},
+			size:     2,
+			expected: [][]string{{"a", "b"}, {"c", "d"}},
+		},
+		{
+			name:     "input not evenly divisible leaves a remainder chunk",
+			input:    []string{"a", "b", "c"},
+			size:     2,
+			expected: [][]string{{"a", "b"}, {"c"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.expected, chunkStrings(testCase.input, testCase.size))
+		})
+	}
+}
+
+func TestSsmRetryerShouldRetryTreatsTransient403AsRetryable(t *testing.T) {
+	t.Parallel()
+
+	retryer := &ssmRetryer{minThrottleDelay: time.Millisecond}
+
+	testCases := []struct {
+		name       string
+		retryCount int
+		expected   bool
+	}{
+		{name: "first attempt is retried", retryCount: 0, expected: true},
+		{name: "attempt below the bound is retried", retryCount: maxTransient403Retries - 1, expected: true},
+		{name: "attempt at the bound is not retried", retryCount: maxTransient403Retries, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := &request.Request{
+				Error:        awserr.New("AccessDenied", "not authorized", nil),
+				RetryCount:   testCase.retryCount,
+				HTTPResponse: &http.Response{StatusCode: 403},
+			}
+
+			assert.Equal(t, testCase.expected, retryer.ShouldRetry(req))
+		})
+	}
+}
+
+func TestSsmRetryerRetryRulesBacksOffThrottlingErrors(t *testing.T) {
+	t.Parallel()
+
+	minThrottleDelay := 100 * time.Millisecond
+
+	testCases := []struct {
+		name       string
+		adaptive   bool
+		retryCount int
+		minDelay   time.Duration
+		maxDelay   time.Duration
+	}{
+		{name: "standard mode first retry", adaptive: false, retryCount: 0, minDelay: minThrottleDelay, maxDelay: minThrottleDelay * 3 / 2},
+		{name: "standard mode second retry backs off further", adaptive: false, retryCount: 1, minDelay: minThrottleDelay * 2, maxDelay: minThrottleDelay * 3},
+		{name: "adaptive mode grows faster than standard mode", adaptive: true, retryCount: 0, minDelay: minThrottleDelay * 2, maxDelay: minThrottleDelay * 3},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			retryer := &ssmRetryer{adaptive: testCase.adaptive, minThrottleDelay: minThrottleDelay}
+			req := &request.Request{
+				Error:      awserr.New("ThrottlingException", "rate exceeded", nil),
+				RetryCount: testCase.retryCount,
+			}
+
+			delay := retryer.RetryRules(req)
+			assert.GreaterOrEqual(t, delay, testCase.minDelay)
+			assert.Less(t, delay, testCase.maxDelay)
+		})
+	}
+}
+
+func TestSsmRetryerRetryRulesFallsBackForNonThrottlingErrors(t *testing.T) {
+	t.Parallel()
+
+	// A 1ns MinRetryDelay removes the default retryer's random jitter, so two
+	// calls for the same request return the same delay.
+	retryer := &ssmRetryer{
+		DefaultRetryer:   client.DefaultRetryer{NumMaxRetries: defaultMaxRetries, MinRetryDelay: time.Nanosecond},
+		minThrottleDelay: 100 * time.Millisecond,
+	}
+	req := &request.Request{
+		Error:      awserr.New("ValidationException", "bad input", nil),
+		RetryCount: 0,
+	}
+
+	assert.Equal(t, retryer.DefaultRetryer.RetryRules(req), retryer.RetryRules(req))
+}
+
+func TestIsTerminalCommandStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		status   string
+		expected bool
+	}{
+		{status: ssm.CommandInvocationStatusSuccess, expected: true},
+		{status: ssm.CommandInvocationStatusCancelled, expected: true},
+		{status: ssm.CommandInvocationStatusTimedOut, expected: true},
+		{status: ssm.CommandInvocationStatusFailed, expected: true},
+		{status: ssm.CommandInvocationStatusPending, expected: false},
+		{status: ssm.CommandInvocationStatusInProgress, expected: false},
+		{status: ssm.CommandInvocationStatusDelayed, expected: false},
+		{status: "", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.status, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.expected, isTerminalCommandStatus(testCase.status))
+		})
+	}
+}
+
+func TestToSsmParameters(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, toSsmParameters(nil))
+	assert.Nil(t, toSsmParameters(map[string][]string{}))
+
+	actual := toSsmParameters(map[string][]string{"commands": {"echo hello", "echo world"}})
+	assert.Equal(t, map[string][]*string{"commands": aws.StringSlice([]string{"echo hello", "echo world"})}, actual)
+}